@@ -0,0 +1,206 @@
+package bencode
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Encode writes the bencode encoding of v to w.
+//
+// Supported types are bool, the signed/unsigned integer kinds, string,
+// []byte, slices, maps with string keys, pointers, and structs annotated
+// with `bencode:"name,omitempty"` tags. Dict keys (map keys and struct
+// field names) are always emitted in ascending byte order, as required by
+// BEP-3. A nil slice is encoded as the empty list `le` and a nil map as
+// the empty dict `de`.
+func Encode(w io.Writer, v any) error {
+	return encodeValue(w, reflect.ValueOf(v))
+}
+
+// Marshal returns the bencode encoding of v.
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeValue(w io.Writer, v reflect.Value) error {
+	if !v.IsValid() {
+		return fmt.Errorf("bencode: cannot encode nil value")
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		i := int64(0)
+		if v.Bool() {
+			i = 1
+		}
+		return encodeInt(w, i)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return encodeInt(w, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return encodeUint(w, v.Uint())
+	case reflect.String:
+		return encodeString(w, []byte(v.String()))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return encodeString(w, v.Bytes())
+		}
+		return encodeList(w, v)
+	case reflect.Array:
+		return encodeList(w, v)
+	case reflect.Map:
+		return encodeMap(w, v)
+	case reflect.Struct:
+		return encodeStruct(w, v)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return fmt.Errorf("bencode: cannot encode nil %s", v.Kind())
+		}
+		return encodeValue(w, v.Elem())
+	default:
+		return fmt.Errorf("bencode: unsupported type %s", v.Type())
+	}
+}
+
+func encodeInt(w io.Writer, i int64) error {
+	_, err := fmt.Fprintf(w, "i%de", i)
+	return err
+}
+
+func encodeUint(w io.Writer, u uint64) error {
+	_, err := fmt.Fprintf(w, "i%de", u)
+	return err
+}
+
+func encodeString(w io.Writer, b []byte) error {
+	if _, err := fmt.Fprintf(w, "%d:", len(b)); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func encodeList(w io.Writer, v reflect.Value) error {
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		_, err := io.WriteString(w, "le")
+		return err
+	}
+
+	if _, err := io.WriteString(w, "l"); err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := encodeValue(w, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "e")
+	return err
+}
+
+func encodeMap(w io.Writer, v reflect.Value) error {
+	if v.IsNil() {
+		_, err := io.WriteString(w, "de")
+		return err
+	}
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("bencode: map key type %s not supported, only string keys are", v.Type().Key())
+	}
+
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	if _, err := io.WriteString(w, "d"); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := encodeString(w, []byte(k.String())); err != nil {
+			return err
+		}
+		if err := encodeValue(w, v.MapIndex(k)); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "e")
+	return err
+}
+
+// encodeStruct writes v's exported fields as a dict, honoring
+// `bencode:"name,omitempty"` tags the same way encoding/json does.
+func encodeStruct(w io.Writer, v reflect.Value) error {
+	type field struct {
+		name string
+		val  reflect.Value
+	}
+
+	t := v.Type()
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+
+		name := sf.Name
+		omitempty := false
+		if tag, ok := sf.Tag.Lookup("bencode"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				if opt == "omitempty" {
+					omitempty = true
+				}
+			}
+		}
+
+		fv := v.Field(i)
+		if omitempty && isEmptyValue(fv) {
+			continue
+		}
+		fields = append(fields, field{name, fv})
+	}
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+
+	if _, err := io.WriteString(w, "d"); err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if err := encodeString(w, []byte(f.name)); err != nil {
+			return err
+		}
+		if err := encodeValue(w, f.val); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "e")
+	return err
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package bencode
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarshalScalars(t *testing.T) {
+	cases := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"int", int(5), "i5e"},
+		{"negative int64", int64(-5), "i-5e"},
+		{"uint64", uint64(5), "i5e"},
+		{"bool true", true, "i1e"},
+		{"bool false", false, "i0e"},
+		{"string", "foo", "3:foo"},
+		{"byte slice", []byte("foo"), "3:foo"},
+		{"nil byte slice", []byte(nil), "0:"},
+		{"nil int slice", []int(nil), "le"},
+		{"nil map", map[string]int(nil), "de"},
+		{"list", []any{1, 2}, "li1ei2ee"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Marshal(tc.in)
+			if err != nil {
+				t.Fatalf("Marshal(%#v) returned error: %v", tc.in, err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Marshal(%#v) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMarshalMapSortsKeys(t *testing.T) {
+	in := map[string]any{"b": 1, "a": 2, "aa": 3}
+
+	got, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	want := "d1:ai2e2:aai3e1:bi1ee"
+	if string(got) != want {
+		t.Errorf("Marshal(%#v) = %q, want %q", in, got, want)
+	}
+}
+
+func TestMarshalStructOmitempty(t *testing.T) {
+	type Info struct {
+		Name    string `bencode:"name"`
+		Length  int    `bencode:"length,omitempty"`
+		Private bool   `bencode:"private,omitempty"`
+	}
+
+	got, err := Marshal(Info{Name: "x"})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	want := "d4:name1:xe"
+	if string(got) != want {
+		t.Errorf("Marshal(%+v) = %q, want %q", Info{Name: "x"}, got, want)
+	}
+
+	got, err = Marshal(Info{Name: "x", Length: 10, Private: true})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	want = "d6:lengthi10e4:name1:x7:privatei1ee"
+	if string(got) != want {
+		t.Errorf("Marshal with all fields set = %q, want %q", got, want)
+	}
+}
+
+func TestMarshalUnmarshalRoundTrip(t *testing.T) {
+	canonical := []byte("d8:announce18:http://example.com4:infod6:lengthi1024e4:name5:movieee")
+
+	var tree map[string]any
+	if err := Unmarshal(canonical, &tree); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	out, err := Marshal(tree)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	if !bytes.Equal(out, canonical) {
+		t.Errorf("Marshal(Unmarshal(x)) = %q, want %q", out, canonical)
+	}
+}
@@ -0,0 +1,267 @@
+package bencode
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Builder receives a stream of bencode parse events. It lets callers
+// consume large bencoded inputs (multi-GB "pieces" fields, multi-file
+// metadata, tracker scrapes) without materializing the whole value into a
+// map[string]any/[]any tree first.
+type Builder interface {
+	Int64(int64)
+	String([]byte)
+	BeginDict()
+	Key([]byte)
+	EndDict()
+	BeginList()
+	EndList()
+}
+
+// Parse reads a single bencoded value from r, reporting each element to b
+// as it is parsed, instead of allocating a tree for the whole value.
+func Parse(r io.Reader, b Builder) error {
+	return newDecoder(r).parse(b)
+}
+
+// parse reads one bencoded value and reports it to b.
+func (d *decoder) parse(b Builder) error {
+	op, err := d.readByte()
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return d.errorf("cannot read structure prefix: %v", err)
+	}
+
+	switch op {
+	case 'd': // dictionary
+		return d.parseDictEvents(b)
+	case 'l': // list
+		return d.parseListEvents(b)
+	case 'i': // integer
+		return d.parseIntegerEvents(b)
+	default: // string
+		d.unreadByte()
+		raw, err := d.readStringBytes()
+		if err != nil {
+			return err
+		}
+		b.String(raw)
+		return nil
+	}
+}
+
+// parseDictEvents parses dict input which is actually a list of tuples in
+// form of d<key_1><val_1>...<key_n><val_n>e
+func (d *decoder) parseDictEvents(b Builder) error {
+	b.BeginDict()
+
+	lastKey := ""
+	seen := make(map[string]bool)
+
+	for {
+		peek, err := d.r.Peek(1)
+		if err != nil {
+			return d.errorf("failed to peek for dict suffix: %v", err)
+		}
+
+		if peek[0] == 'e' {
+			break
+		}
+
+		op, err := d.readByte()
+		if err != nil {
+			return d.errorf("failed to read dict key prefix: %v", err)
+		}
+		if op < '0' || op > '9' {
+			return d.errorf("dict key must be a string, got prefix %q", op)
+		}
+		d.unreadByte()
+
+		keyBytes, err := d.readStringBytes()
+		if err != nil {
+			return fmt.Errorf("failed to parse dict key: %w", err)
+		}
+		keyStr := string(keyBytes)
+
+		if d.strict {
+			if msg := dictKeyViolation(seen, lastKey, keyStr); msg != "" {
+				return d.errorf("%s", msg)
+			}
+			seen[keyStr] = true
+			lastKey = keyStr
+		}
+
+		b.Key(keyBytes)
+
+		if err := d.parse(b); err != nil {
+			return fmt.Errorf("failed to parse dict val: %w", err)
+		}
+	}
+
+	if err := d.discard(1); err != nil { // trim suffix
+		return d.errorf("failed to discard dict suffix: %v", err)
+	}
+
+	b.EndDict()
+	return nil
+}
+
+// parseListEvents parses list input in form of l<el_1><...<el_n>e
+func (d *decoder) parseListEvents(b Builder) error {
+	b.BeginList()
+
+	for {
+		peek, err := d.r.Peek(1)
+		if err != nil {
+			return d.errorf("failed to peek for list suffix: %v", err)
+		}
+
+		if peek[0] == 'e' {
+			break
+		}
+
+		if err := d.parse(b); err != nil {
+			return fmt.Errorf("failed to parse list: %w", err)
+		}
+	}
+
+	if err := d.discard(1); err != nil { // trim suffix
+		return d.errorf("failed to discard list suffix: %v", err)
+	}
+
+	b.EndList()
+	return nil
+}
+
+// parseIntegerEvents parses integer input in form of i<sign><value>e
+func (d *decoder) parseIntegerEvents(b Builder) error {
+	result, err := d.readInt64()
+	if err != nil {
+		return err
+	}
+
+	b.Int64(result)
+	return nil
+}
+
+// readInt64 reads an integer input in form of i<sign><value>e, having
+// already consumed the leading "i".
+func (d *decoder) readInt64() (int64, error) {
+	strVal, err := d.readSlice('e')
+	if err != nil {
+		return 0, d.errorf("failed to read integer value: %v", err)
+	}
+
+	digits := strings.TrimRight(string(strVal), "e")
+
+	if d.strict {
+		if err := validateCanonicalInt(digits); err != nil {
+			return 0, d.errorf("non-canonical integer %q: %v", digits, err)
+		}
+	}
+
+	result, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, d.errorf("failed to parse integer value: %v", err)
+	}
+
+	return result, nil
+}
+
+// readStringBytes reads a bencode string's length prefix and payload,
+// returning a freshly-allocated copy of the payload bytes (safe to retain
+// past the next read, unlike the decoder's internal peek buffer).
+func (d *decoder) readStringBytes() ([]byte, error) {
+	strLen, err := d.readSlice(':')
+	if err != nil {
+		return nil, d.errorf("failed to read string length: %v", err)
+	}
+
+	lenDigits := strings.TrimRight(string(strLen), ":")
+
+	if d.strict && len(lenDigits) > 1 && lenDigits[0] == '0' {
+		return nil, d.errorf("non-canonical string length %q: leading zero", lenDigits)
+	}
+
+	length, err := strconv.Atoi(lenDigits)
+	if err != nil {
+		return nil, d.errorf("failed to parse string length: %v", err)
+	}
+
+	if length == 0 {
+		return []byte{}, nil
+	}
+
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(d.r, buf); err != nil {
+		return nil, d.errorf("failed to read string value: %v", err)
+	}
+	d.offset += int64(length)
+
+	return buf, nil
+}
+
+// treeBuilder is the default Builder, reproducing the map[string]any/[]any
+// tree that Decode has always returned, on top of the streaming parser.
+type treeBuilder struct {
+	result     any
+	stack      []any // *listFrame or *dictFrame
+	rawStrings bool  // report []byte instead of string for string values
+}
+
+type listFrame struct {
+	items []any
+}
+
+type dictFrame struct {
+	m   map[string]any
+	key string
+}
+
+func (t *treeBuilder) push(v any) {
+	if len(t.stack) == 0 {
+		t.result = v
+		return
+	}
+
+	switch f := t.stack[len(t.stack)-1].(type) {
+	case *listFrame:
+		f.items = append(f.items, v)
+	case *dictFrame:
+		f.m[f.key] = v
+	}
+}
+
+func (t *treeBuilder) Int64(i int64) { t.push(int(i)) }
+
+func (t *treeBuilder) String(s []byte) {
+	if t.rawStrings {
+		t.push(s)
+	} else {
+		t.push(string(s))
+	}
+}
+
+func (t *treeBuilder) BeginList() { t.stack = append(t.stack, &listFrame{}) }
+func (t *treeBuilder) BeginDict() { t.stack = append(t.stack, &dictFrame{m: make(map[string]any)}) }
+
+func (t *treeBuilder) Key(k []byte) {
+	t.stack[len(t.stack)-1].(*dictFrame).key = string(k)
+}
+
+func (t *treeBuilder) EndList() {
+	f := t.stack[len(t.stack)-1].(*listFrame)
+	t.stack = t.stack[:len(t.stack)-1]
+	t.push(f.items)
+}
+
+func (t *treeBuilder) EndDict() {
+	f := t.stack[len(t.stack)-1].(*dictFrame)
+	t.stack = t.stack[:len(t.stack)-1]
+	t.push(f.m)
+}
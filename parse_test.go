@@ -0,0 +1,85 @@
+package bencode
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// eventLogBuilder is a Builder that records each callback as a string, to
+// verify Parse drives a custom (non-treeBuilder) Builder through the right
+// sequence of events.
+type eventLogBuilder struct {
+	events []string
+}
+
+func (b *eventLogBuilder) Int64(i int64)   { b.events = append(b.events, fmt.Sprintf("Int64(%d)", i)) }
+func (b *eventLogBuilder) String(s []byte) { b.events = append(b.events, fmt.Sprintf("String(%q)", s)) }
+func (b *eventLogBuilder) BeginDict()      { b.events = append(b.events, "BeginDict") }
+func (b *eventLogBuilder) Key(k []byte)    { b.events = append(b.events, fmt.Sprintf("Key(%q)", k)) }
+func (b *eventLogBuilder) EndDict()        { b.events = append(b.events, "EndDict") }
+func (b *eventLogBuilder) BeginList()      { b.events = append(b.events, "BeginList") }
+func (b *eventLogBuilder) EndList()        { b.events = append(b.events, "EndList") }
+
+func TestParseDrivesCustomBuilder(t *testing.T) {
+	var b eventLogBuilder
+	if err := Parse(strings.NewReader("d3:bar4:spam3:fooli1ei2eee"), &b); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	want := []string{
+		"BeginDict",
+		`Key("bar")`,
+		`String("spam")`,
+		`Key("foo")`,
+		"BeginList",
+		"Int64(1)",
+		"Int64(2)",
+		"EndList",
+		"EndDict",
+	}
+
+	if !reflect.DeepEqual(b.events, want) {
+		t.Errorf("Parse events = %v, want %v", b.events, want)
+	}
+}
+
+func TestParseEmptyContainers(t *testing.T) {
+	cases := []struct {
+		input string
+		want  []string
+	}{
+		{"de", []string{"BeginDict", "EndDict"}},
+		{"le", []string{"BeginList", "EndList"}},
+	}
+
+	for _, tc := range cases {
+		var b eventLogBuilder
+		if err := Parse(strings.NewReader(tc.input), &b); err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tc.input, err)
+		}
+		if !reflect.DeepEqual(b.events, tc.want) {
+			t.Errorf("Parse(%q) events = %v, want %v", tc.input, b.events, tc.want)
+		}
+	}
+}
+
+// TestDecodeMatchesTreeBuilderShape checks that Decode, now implemented on
+// top of Parse via the default treeBuilder, still produces the same
+// map[string]any/[]any/string/int tree it always has.
+func TestDecodeMatchesTreeBuilderShape(t *testing.T) {
+	got, err := Decode(strings.NewReader("d3:bar4:spam3:fooli1ei2eee"))
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+
+	want := map[string]any{
+		"bar": "spam",
+		"foo": []any{1, 2},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package bencode
+
+import (
+	"fmt"
+	"io"
+)
+
+// DecodeBytes reads and returns a single bencoded value from reader, like
+// Decode, but reports bencode string values as []byte instead of string.
+func DecodeBytes(reader io.Reader) (any, error) {
+	d := newDecoder(reader)
+	d.rawStrings = true
+	return d.decodeAny()
+}
+
+// Node pairs a decoded value with the half-open byte range [Start, End) in
+// the input it was parsed from. For dicts and lists, Value holds
+// map[string]*Node and []*Node respectively, so every nested value carries
+// its own range too. This lets callers hash the exact source bytes of a
+// sub-value - e.g. a torrent's "info" dict - without re-encoding it, which
+// is how info-hashes are computed canonically.
+type Node struct {
+	Value      any
+	Start, End int64
+}
+
+// DecodeNode decodes a single bencoded value from r into a Node tree. If
+// rawStrings is true, string values are reported as []byte instead of
+// string. If strict is true, the input is validated against BEP-3
+// canonical form exactly as DecodeStrict does - important when the
+// resulting byte ranges will be hashed for an info-hash, since a
+// non-canonical "info" dict would hash differently than its canonical
+// re-encoding.
+func DecodeNode(r io.Reader, rawStrings, strict bool) (*Node, error) {
+	d := newDecoder(r)
+	d.strict = strict
+	return d.decodeNode(rawStrings)
+}
+
+// decodeNode mirrors parse's dict/list walk rather than driving a Builder:
+// Builder callbacks carry no byte offsets, so recovering
+// [Start, End) ranges for nested values needs direct access to d.offset
+// around each recursive call. The strict-mode dict key bookkeeping is
+// shared with parseDictEvents via dictKeyViolation to keep the two walks
+// from drifting apart.
+func (d *decoder) decodeNode(rawStrings bool) (*Node, error) {
+	start := d.offset
+
+	op, err := d.readByte()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, d.errorf("cannot read structure prefix: %v", err)
+	}
+
+	var value any
+	switch op {
+	case 'd':
+		value, err = d.parseDictNode(rawStrings)
+	case 'l':
+		value, err = d.parseListNode(rawStrings)
+	case 'i':
+		value, err = d.readInt64()
+	default:
+		d.unreadByte()
+		var raw []byte
+		raw, err = d.readStringBytes()
+		if rawStrings {
+			value = raw
+		} else {
+			value = string(raw)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Node{Value: value, Start: start, End: d.offset}, nil
+}
+
+func (d *decoder) parseDictNode(rawStrings bool) (map[string]*Node, error) {
+	result := make(map[string]*Node)
+	lastKey := ""
+	seen := make(map[string]bool)
+
+	for {
+		peek, err := d.r.Peek(1)
+		if err != nil {
+			return nil, d.errorf("failed to peek for dict suffix: %v", err)
+		}
+
+		if peek[0] == 'e' {
+			break
+		}
+
+		op, err := d.readByte()
+		if err != nil {
+			return nil, d.errorf("failed to read dict key prefix: %v", err)
+		}
+		if op < '0' || op > '9' {
+			return nil, d.errorf("dict key must be a string, got prefix %q", op)
+		}
+		d.unreadByte()
+
+		keyBytes, err := d.readStringBytes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dict key: %w", err)
+		}
+		keyStr := string(keyBytes)
+
+		if d.strict {
+			if msg := dictKeyViolation(seen, lastKey, keyStr); msg != "" {
+				return nil, d.errorf("%s", msg)
+			}
+			seen[keyStr] = true
+			lastKey = keyStr
+		}
+
+		val, err := d.decodeNode(rawStrings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dict val: %w", err)
+		}
+		result[keyStr] = val
+	}
+
+	if err := d.discard(1); err != nil { // trim suffix
+		return nil, d.errorf("failed to discard dict suffix: %v", err)
+	}
+
+	return result, nil
+}
+
+func (d *decoder) parseListNode(rawStrings bool) ([]*Node, error) {
+	var result []*Node
+
+	for {
+		peek, err := d.r.Peek(1)
+		if err != nil {
+			return nil, d.errorf("failed to peek for list suffix: %v", err)
+		}
+
+		if peek[0] == 'e' {
+			break
+		}
+
+		el, err := d.decodeNode(rawStrings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse list: %w", err)
+		}
+		result = append(result, el)
+	}
+
+	if err := d.discard(1); err != nil { // trim suffix
+		return nil, d.errorf("failed to discard list suffix: %v", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package bencode
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBytesReturnsByteStrings(t *testing.T) {
+	got, err := DecodeBytes(strings.NewReader("d4:name5:movie6:lengthi1024ee"))
+	if err != nil {
+		t.Fatalf("DecodeBytes returned error: %v", err)
+	}
+
+	dict, ok := got.(map[string]any)
+	if !ok {
+		t.Fatalf("DecodeBytes = %#v, want map[string]any", got)
+	}
+
+	name, ok := dict["name"].([]byte)
+	if !ok {
+		t.Fatalf("dict[%q] = %#v (%T), want []byte", "name", dict["name"], dict["name"])
+	}
+	if string(name) != "movie" {
+		t.Errorf("name = %q, want %q", name, "movie")
+	}
+}
+
+// TestDecodeNodeOffsetsBoundNestedValue checks that a nested Node's
+// [Start, End) range slices out exactly its own source bytes - the
+// property the "info" dict info-hash computation depends on.
+func TestDecodeNodeOffsetsBoundNestedValue(t *testing.T) {
+	input := "d8:announce18:http://example.com4:infod6:lengthi1024e4:name5:movieee"
+
+	root, err := DecodeNode(strings.NewReader(input), false, false)
+	if err != nil {
+		t.Fatalf("DecodeNode returned error: %v", err)
+	}
+
+	dict, ok := root.Value.(map[string]*Node)
+	if !ok {
+		t.Fatalf("root.Value = %#v, want map[string]*Node", root.Value)
+	}
+
+	info, ok := dict["info"]
+	if !ok {
+		t.Fatal(`dict["info"] missing`)
+	}
+
+	raw := input[info.Start:info.End]
+	wantRaw := "d6:lengthi1024e4:name5:moviee"
+	if raw != wantRaw {
+		t.Errorf("info byte range = %q, want %q", raw, wantRaw)
+	}
+
+	// The sliced-out bytes must be independently parseable, without
+	// re-encoding, and equal to decoding the "info" field the ordinary way.
+	reparsed, err := Decode(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Decode(info range) returned error: %v", err)
+	}
+	want := map[string]any{"length": 1024, "name": "movie"}
+	if !reflect.DeepEqual(reparsed, want) {
+		t.Errorf("Decode(info range) = %#v, want %#v", reparsed, want)
+	}
+}
+
+func TestDecodeNodeStrict(t *testing.T) {
+	const dup = "d3:fooi1e3:fooi2ee"
+
+	if _, err := DecodeNode(strings.NewReader(dup), false, true); err == nil {
+		t.Error("DecodeNode(strict=true) with duplicate dict key = nil error, want rejection")
+	}
+	if _, err := DecodeNode(strings.NewReader(dup), false, false); err != nil {
+		t.Errorf("DecodeNode(strict=false) unexpectedly rejected duplicate key input: %v", err)
+	}
+}
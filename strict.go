@@ -0,0 +1,87 @@
+package bencode
+
+import (
+	"fmt"
+	"io"
+)
+
+// DecoderOptions configures the behavior of a Decoder.
+type DecoderOptions struct {
+	// Strict rejects bencode input that is not in BEP-3 canonical form:
+	// integers with leading zeros, negative zero, the empty integer,
+	// strings with leading zeros in their length prefix, dict keys that
+	// are not strings, duplicate dict keys, and dict keys that are not in
+	// ascending byte order.
+	Strict bool
+
+	// RawStrings reports bencode string values as []byte instead of
+	// string, so that binary data (e.g. the "pieces" field of a
+	// .torrent) can be read without a lossy byte/string reinterpretation.
+	RawStrings bool
+}
+
+// NewDecoderOptions returns a new Decoder that reads from r and applies
+// opts.
+func NewDecoderOptions(r io.Reader, opts DecoderOptions) *Decoder {
+	d := newDecoder(r)
+	d.strict = opts.Strict
+	d.rawStrings = opts.RawStrings
+	return &Decoder{d: d}
+}
+
+// DecodeStrict reads and returns a single bencoded value from reader, like
+// Decode, but rejects any input that is not in BEP-3 canonical form.
+func DecodeStrict(reader io.Reader) (any, error) {
+	d := newDecoder(reader)
+	d.strict = true
+	return d.decodeAny()
+}
+
+// validateCanonicalInt rejects integer digit strings (the bytes between
+// "i" and "e", exclusive) that are not in BEP-3 canonical form.
+func validateCanonicalInt(digits string) error {
+	if digits == "" {
+		return fmt.Errorf("empty integer")
+	}
+
+	unsigned := digits
+	neg := digits[0] == '-'
+	if neg {
+		unsigned = digits[1:]
+	}
+
+	if unsigned == "" || !isDigits(unsigned) {
+		return fmt.Errorf("not a valid integer")
+	}
+	if neg && unsigned == "0" {
+		return fmt.Errorf("negative zero")
+	}
+	if len(unsigned) > 1 && unsigned[0] == '0' {
+		return fmt.Errorf("leading zero")
+	}
+
+	return nil
+}
+
+// dictKeyViolation reports a non-empty message if key is not a valid next
+// key in a strict-mode dict whose prior keys are seen, the last of which
+// was lastKey. Shared by the streaming and Node dict parsers so the two
+// stay in lockstep on what counts as canonical.
+func dictKeyViolation(seen map[string]bool, lastKey, key string) string {
+	if seen[key] {
+		return fmt.Sprintf("duplicate dict key %q", key)
+	}
+	if len(seen) > 0 && key < lastKey {
+		return fmt.Sprintf("dict keys not in ascending order: %q before %q", lastKey, key)
+	}
+	return ""
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package bencode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeStrictRejectsNonCanonicalInput(t *testing.T) {
+	cases := []struct {
+		name string
+		// rejectedEvenNonStrict inputs are malformed independently of
+		// strict mode (e.g. an empty integer can never be parsed, and a
+		// dict key whose prefix is not a string length is always refused),
+		// so plain Decode rejects them too.
+		rejectedEvenNonStrict bool
+		input                 string
+	}{
+		{name: "leading zero integer", input: "i03e"},
+		{name: "negative zero", input: "i-0e"},
+		{name: "empty integer", input: "ie", rejectedEvenNonStrict: true},
+		{name: "leading zero string length", input: "03:foo"},
+		{name: "non-string dict key", input: "di5e3:fooe", rejectedEvenNonStrict: true},
+		{name: "duplicate dict key", input: "d3:fooi1e3:fooi2ee"},
+		{name: "dict keys out of order", input: "d3:fooi1e3:bari2ee"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := DecodeStrict(strings.NewReader(tc.input))
+			if err == nil {
+				t.Fatalf("DecodeStrict(%q) = nil error, want rejection", tc.input)
+			}
+
+			_, err = Decode(strings.NewReader(tc.input))
+			if tc.rejectedEvenNonStrict && err == nil {
+				t.Fatalf("Decode(%q) = nil error, want rejection even without strict mode", tc.input)
+			}
+			if !tc.rejectedEvenNonStrict && err != nil {
+				t.Fatalf("Decode(%q) unexpectedly rejected non-strict input: %v", tc.input, err)
+			}
+		})
+	}
+}
+
+func TestDecodeStrictAcceptsCanonicalInput(t *testing.T) {
+	cases := []string{
+		"i0e",
+		"i-5e",
+		"i42e",
+		"4:spam",
+		"l4:spam4:eggse",
+		"d3:bari2e3:fooi1ee",
+	}
+
+	for _, in := range cases {
+		if _, err := DecodeStrict(strings.NewReader(in)); err != nil {
+			t.Errorf("DecodeStrict(%q) returned unexpected error: %v", in, err)
+		}
+	}
+}
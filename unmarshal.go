@@ -0,0 +1,197 @@
+package bencode
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// SyntaxError reports a malformed bencode input, together with the byte
+// offset in the input at which the problem was detected.
+type SyntaxError struct {
+	Offset int64
+	What   string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("bencode: %s (offset %d)", e.What, e.Offset)
+}
+
+// Decoder reads and decodes bencode values from an input stream, mirroring
+// the shape of json.Decoder.
+type Decoder struct {
+	d *decoder
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{d: newDecoder(r)}
+}
+
+// Decode reads the next bencode-encoded value from its input and stores it
+// in the value pointed to by v.
+func (dec *Decoder) Decode(v any) error {
+	tree, err := dec.d.decodeAny()
+	if err != nil {
+		return err
+	}
+	return unmarshalValue(tree, reflect.ValueOf(v))
+}
+
+// Unmarshal parses bencoded data and stores the result in the value pointed
+// to by v.
+func Unmarshal(data []byte, v any) error {
+	return NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func unmarshalValue(tree any, rv reflect.Value) error {
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("bencode: Unmarshal requires a non-nil pointer, got %T", rv.Interface())
+	}
+	return assign(tree, rv.Elem())
+}
+
+// assign copies the decoded tree value (a map[string]any, []any, string,
+// []byte or int, as produced by decoder.decodeAny) into rv. []byte only
+// appears when the Decoder was built with DecoderOptions.RawStrings.
+func assign(tree any, rv reflect.Value) error {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return assign(tree, rv.Elem())
+	}
+
+	if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		rv.Set(reflect.ValueOf(tree))
+		return nil
+	}
+
+	switch t := tree.(type) {
+	case map[string]any:
+		return assignDict(t, rv)
+	case []any:
+		return assignList(t, rv)
+	case string:
+		return assignString(t, rv)
+	case []byte:
+		return assignString(string(t), rv)
+	case int:
+		return assignInt(int64(t), rv)
+	default:
+		return fmt.Errorf("bencode: cannot unmarshal %T into %s", tree, rv.Type())
+	}
+}
+
+func assignInt(i int64, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i < 0 {
+			return fmt.Errorf("bencode: cannot unmarshal negative integer into %s", rv.Type())
+		}
+		rv.SetUint(uint64(i))
+	case reflect.Bool:
+		rv.SetBool(i != 0)
+	default:
+		return fmt.Errorf("bencode: cannot unmarshal integer into %s", rv.Type())
+	}
+	return nil
+}
+
+func assignString(s string, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("bencode: cannot unmarshal string into %s", rv.Type())
+		}
+		rv.SetBytes([]byte(s))
+	default:
+		return fmt.Errorf("bencode: cannot unmarshal string into %s", rv.Type())
+	}
+	return nil
+}
+
+func assignList(list []any, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Slice:
+		out := reflect.MakeSlice(rv.Type(), len(list), len(list))
+		for i, el := range list {
+			if err := assign(el, out.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(out)
+		return nil
+	case reflect.Array:
+		if len(list) != rv.Len() {
+			return fmt.Errorf("bencode: cannot unmarshal list of length %d into %s", len(list), rv.Type())
+		}
+		for i, el := range list {
+			if err := assign(el, rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("bencode: cannot unmarshal list into %s", rv.Type())
+	}
+}
+
+func assignDict(dict map[string]any, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Struct:
+		return assignStruct(dict, rv)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("bencode: cannot unmarshal dict into %s, only string-keyed maps are supported", rv.Type())
+		}
+		out := reflect.MakeMapWithSize(rv.Type(), len(dict))
+		for k, v := range dict {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := assign(v, elem); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), elem)
+		}
+		rv.Set(out)
+		return nil
+	default:
+		return fmt.Errorf("bencode: cannot unmarshal dict into %s", rv.Type())
+	}
+}
+
+func assignStruct(dict map[string]any, rv reflect.Value) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+
+		name := sf.Name
+		if tag, ok := sf.Tag.Lookup("bencode"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+		}
+
+		val, ok := dict[name]
+		if !ok {
+			continue
+		}
+		if err := assign(val, rv.Field(i)); err != nil {
+			return fmt.Errorf("bencode: field %q: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package bencode
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalNonStringDictKeyReturnsSyntaxError(t *testing.T) {
+	var v map[string]any
+	err := Unmarshal([]byte("di5e3:fooe"), &v)
+	if err == nil {
+		t.Fatal("Unmarshal with non-string dict key = nil error, want *SyntaxError")
+	}
+
+	var synErr *SyntaxError
+	if !errors.As(err, &synErr) {
+		t.Fatalf("Unmarshal error = %v (%T), want *SyntaxError", err, err)
+	}
+	if synErr.Offset == 0 {
+		t.Errorf("SyntaxError.Offset = 0, want the offset of the offending key")
+	}
+}
+
+func TestUnmarshalStruct(t *testing.T) {
+	type Info struct {
+		Name    string `bencode:"name"`
+		Length  int    `bencode:"length"`
+		Private bool   `bencode:"private"`
+	}
+
+	var got Info
+	if err := Unmarshal([]byte("d6:lengthi1024e4:name5:movie7:privatei1ee"), &got); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	want := Info{Name: "movie", Length: 1024, Private: true}
+	if got != want {
+		t.Errorf("Unmarshal = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnmarshalMap(t *testing.T) {
+	var got map[string]int
+	if err := Unmarshal([]byte("d3:bari2e3:fooi1ee"), &got); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	want := map[string]int{"foo": 1, "bar": 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal = %v, want %v", got, want)
+	}
+}
+
+func TestUnmarshalSlice(t *testing.T) {
+	var got []string
+	if err := Unmarshal([]byte("l3:foo3:bare"), &got); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	want := []string{"foo", "bar"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal = %v, want %v", got, want)
+	}
+}
+
+func TestUnmarshalPointer(t *testing.T) {
+	var got *int
+	if err := Unmarshal([]byte("i42e"), &got); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	if got == nil || *got != 42 {
+		t.Errorf("Unmarshal into *int = %v, want pointer to 42", got)
+	}
+}
+
+func TestDecoderRawStringsUnmarshalsIntoByteSlice(t *testing.T) {
+	type Info struct {
+		Pieces []byte `bencode:"pieces"`
+	}
+
+	dec := NewDecoderOptions(strings.NewReader("d6:pieces4:abcde"), DecoderOptions{RawStrings: true})
+
+	var got Info
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+
+	if string(got.Pieces) != "abcd" {
+		t.Errorf("Pieces = %q, want %q", got.Pieces, "abcd")
+	}
+}
+
+func TestDecoderDecodeReadsSuccessiveValues(t *testing.T) {
+	dec := NewDecoder(strings.NewReader("i1ei2e"))
+
+	var a, b int
+	if err := dec.Decode(&a); err != nil {
+		t.Fatalf("first Decode returned error: %v", err)
+	}
+	if err := dec.Decode(&b); err != nil {
+		t.Fatalf("second Decode returned error: %v", err)
+	}
+
+	if a != 1 || b != 2 {
+		t.Errorf("Decode sequence = %d, %d, want 1, 2", a, b)
+	}
+}